@@ -0,0 +1,142 @@
+package vmwarecloudsimple
+
+// Copyright (c) Microsoft and contributors.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/adal"
+	"github.com/Azure/go-autorest/autorest/azure"
+	"github.com/Azure/go-autorest/autorest/azure/auth"
+)
+
+// msiProbeTimeout bounds how long NewAuthorizerFromMSI waits for the instance metadata service to
+// respond before concluding the host has no managed identity available.
+const msiProbeTimeout = 2 * time.Second
+
+// NewAuthorizerFromEnvironment creates an authorizer from the environment variables documented at
+// https://docs.microsoft.com/en-us/go/azure/azure-sdk-go-authorization#use-environment-based-authentication.
+func NewAuthorizerFromEnvironment() (autorest.Authorizer, error) {
+	return auth.NewAuthorizerFromEnvironment()
+}
+
+// NewAuthorizerFromCLI creates an authorizer from the locally installed and logged-in Azure CLI.
+func NewAuthorizerFromCLI() (autorest.Authorizer, error) {
+	return auth.NewAuthorizerFromCLI()
+}
+
+// NewAuthorizerFromMSI creates an authorizer from the managed service identity endpoint available to the
+// current host. Unlike adal.NewServicePrincipalTokenFromMSI alone, which only discovers whether the MSI
+// endpoint is configured, this also probes it with a bounded-timeout token request so that calling this
+// function off an MSI-enabled host fails fast instead of deferring the failure to the first real
+// request.
+func NewAuthorizerFromMSI() (autorest.Authorizer, error) {
+	msiEndpoint, err := adal.GetMSIVMEndpoint()
+	if err != nil {
+		return nil, err
+	}
+	// Probe on a disposable token with a short-timeout sender, so a host with no MSI available fails
+	// fast here rather than on the first real request. The authorizer it returns is backed by a
+	// separate, freshly constructed token left on adal's default sender, so the probe's timeout never
+	// caps the production token refreshes BearerAuthorizer triggers lazily on first use.
+	probe, err := adal.NewServicePrincipalTokenFromMSI(msiEndpoint, azure.PublicCloud.ResourceManagerEndpoint)
+	if err != nil {
+		return nil, err
+	}
+	probe.SetSender(&http.Client{Timeout: msiProbeTimeout})
+	if err := probe.Refresh(); err != nil {
+		return nil, err
+	}
+
+	spt, err := adal.NewServicePrincipalTokenFromMSI(msiEndpoint, azure.PublicCloud.ResourceManagerEndpoint)
+	if err != nil {
+		return nil, err
+	}
+	return autorest.NewBearerAuthorizer(spt), nil
+}
+
+// newAuthorizerFromWorkloadIdentity creates an authorizer from a workload identity federated token file,
+// as described at
+// https://learn.microsoft.com/en-us/azure/aks/workload-identity-overview, using the
+// AZURE_FEDERATED_TOKEN_FILE, AZURE_CLIENT_ID and AZURE_TENANT_ID environment variables set by AKS.
+func newAuthorizerFromWorkloadIdentity() (autorest.Authorizer, error) {
+	tokenFile, ok := os.LookupEnv("AZURE_FEDERATED_TOKEN_FILE")
+	if !ok {
+		return nil, errors.New("vmwarecloudsimple: AZURE_FEDERATED_TOKEN_FILE is not set")
+	}
+	clientID := os.Getenv("AZURE_CLIENT_ID")
+	tenantID := os.Getenv("AZURE_TENANT_ID")
+	if clientID == "" || tenantID == "" {
+		return nil, errors.New("vmwarecloudsimple: AZURE_CLIENT_ID and AZURE_TENANT_ID must be set alongside AZURE_FEDERATED_TOKEN_FILE")
+	}
+
+	oauthConfig, err := adal.NewOAuthConfig(azure.PublicCloud.ActiveDirectoryEndpoint, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	// The federated token file is rotated by the platform (e.g. AKS) well before the JWT it contains
+	// expires, so re-read it on every refresh rather than caching the first value.
+	readToken := func() (string, error) {
+		token, err := os.ReadFile(tokenFile)
+		if err != nil {
+			return "", err
+		}
+		return string(token), nil
+	}
+	spt, err := adal.NewServicePrincipalTokenFromFederatedTokenCallback(*oauthConfig, clientID, readToken, azure.PublicCloud.ResourceManagerEndpoint)
+	if err != nil {
+		return nil, err
+	}
+	return autorest.NewBearerAuthorizer(spt), nil
+}
+
+// NewDefaultAuthorizer tries, in order, a workload identity federated token file, an environment-based
+// authorizer, managed service identity, and finally the Azure CLI, returning the first one that
+// succeeds. It is the authorizer NewAvailableOperationsClientWithCredentials always uses.
+//
+// Workload identity is checked ahead of NewAuthorizerFromEnvironment rather than after it:
+// auth.NewAuthorizerFromEnvironment falls back to IMDS MSI on its own once no client secret, client
+// certificate or username/password environment variables are set, so on an AKS node that exposes both
+// workload identity and MSI, deferring to it first would always return an MSI authorizer and the
+// explicit workload-identity branch below would never run.
+func NewDefaultAuthorizer() (autorest.Authorizer, error) {
+	if a, err := newAuthorizerFromWorkloadIdentity(); err == nil {
+		return a, nil
+	}
+	if a, err := NewAuthorizerFromEnvironment(); err == nil {
+		return a, nil
+	}
+	if a, err := NewAuthorizerFromMSI(); err == nil {
+		return a, nil
+	}
+	return NewAuthorizerFromCLI()
+}
+
+// NewAvailableOperationsClientWithCredentials creates an AvailableOperationsClient whose Authorizer is
+// resolved by NewDefaultAuthorizer, so callers do not have to wire autorest.Authorizer by hand.
+func NewAvailableOperationsClientWithCredentials(ctx context.Context, referer string, regionID string, subscriptionID string) (AvailableOperationsClient, error) {
+	client := NewAvailableOperationsClient(referer, regionID, subscriptionID)
+	authorizer, err := NewDefaultAuthorizer()
+	if err != nil {
+		return AvailableOperationsClient{}, err
+	}
+	client.Authorizer = authorizer
+	return client, nil
+}
@@ -92,8 +92,11 @@ func (client AvailableOperationsClient) ListPreparer(ctx context.Context) (*http
 // ListSender sends the List request. The method will close the
 // http.Response Body if it receives an error.
 func (client AvailableOperationsClient) ListSender(req *http.Request) (*http.Response, error) {
-	return autorest.SendWithSender(client, req,
-		autorest.DoRetryForStatusCodes(client.RetryAttempts, client.RetryDuration, autorest.StatusCodesForRetry...))
+	policy := client.RetryPolicy
+	if policy.MaxAttempts == 0 {
+		policy = DefaultRetryPolicy()
+	}
+	return autorest.SendWithSender(client, req, DoRetryWithPolicy(policy))
 }
 
 // ListResponder handles the response to the List request. The method always
@@ -0,0 +1,61 @@
+// Package vmwarecloudsimple implements the Azure ARM Vmwarecloudsimple service API version 2019-04-01.
+//
+// The description of the new service.
+package vmwarecloudsimple
+
+// Copyright (c) Microsoft and contributors.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Code generated by Microsoft (R) AutoRest Code Generator.
+// Changes may cause incorrect behavior and will be lost if the code is regenerated.
+
+import (
+	"github.com/Azure/go-autorest/autorest"
+)
+
+// fqdn is the package's fully qualified name, used as the prefix for tracing span names.
+const fqdn = "github.com/Azure/azure-sdk-for-go/services/vmwarecloudsimple/mgmt/2019-04-01/vmwarecloudsimple"
+
+// DefaultBaseURI is the default URI used for the service Vmwarecloudsimple
+const DefaultBaseURI = "https://management.azure.com"
+
+// BaseClient is the base client for Vmwarecloudsimple
+type BaseClient struct {
+	autorest.Client
+	BaseURI        string
+	Referer        string
+	RegionID       string
+	SubscriptionID string
+	// RetryPolicy overrides the default autorest retry loop with jittered exponential backoff and
+	// Retry-After handling. See DefaultRetryPolicy and DoRetryWithPolicy.
+	RetryPolicy RetryPolicy
+}
+
+// New creates an instance of the BaseClient client.
+func New(referer string, regionID string, subscriptionID string) BaseClient {
+	return NewWithBaseURI(DefaultBaseURI, referer, regionID, subscriptionID)
+}
+
+// NewWithBaseURI creates an instance of the BaseClient client using a custom endpoint. Use this for
+// Azure clouds with a non-standard endpoint, e.g. sovereign clouds or Azure Stack.
+func NewWithBaseURI(baseURI string, referer string, regionID string, subscriptionID string) BaseClient {
+	return BaseClient{
+		Client:         autorest.NewClientWithUserAgent(UserAgent()),
+		BaseURI:        baseURI,
+		Referer:        referer,
+		RegionID:       regionID,
+		SubscriptionID: subscriptionID,
+		RetryPolicy:    DefaultRetryPolicy(),
+	}
+}
@@ -0,0 +1,141 @@
+// Package fake provides an in-memory HTTP test server for vmwarecloudsimple so that callers can exercise
+// AvailableOperationsClient, and the sibling clients as they gain fake support, without contacting ARM.
+package fake
+
+// Copyright (c) Microsoft and contributors.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/vmwarecloudsimple/mgmt/2019-04-01/vmwarecloudsimple"
+)
+
+// operationsPage is the query parameter used to drive the handler's second (and final) page, so
+// ListComplete and the prefetch iterator can be exercised against a real two-page response.
+const operationsPage = "page"
+
+// Server is an in-memory implementation of the vmwarecloudsimple operations endpoint, backed by an
+// httptest.Server. Private clouds, virtual machines, dedicated cloud nodes and SKUs endpoints will be
+// added here as their clients land in this package.
+type Server struct {
+	// URL is the base URL of the fake server, suitable for
+	// vmwarecloudsimple.NewAvailableOperationsClientWithBaseURI.
+	URL string
+
+	httpServer *httptest.Server
+
+	mu                sync.Mutex
+	forceStatus       int
+	failuresRemaining int
+	operations        []vmwarecloudsimple.AvailableOperation
+}
+
+// NewServer starts a fake vmwarecloudsimple server and registers t.Cleanup to shut it down.
+func NewServer(t *testing.T) *Server {
+	s := &Server{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/providers/Microsoft.VMwareCloudSimple/operations", s.handleOperations)
+	s.httpServer = httptest.NewServer(mux)
+	t.Cleanup(s.httpServer.Close)
+	s.URL = s.httpServer.URL
+	return s
+}
+
+// ForceStatus makes every subsequent request respond with the given status code instead of its normal
+// canned response, until cleared by passing 0. Use http.StatusTooManyRequests or
+// http.StatusServiceUnavailable to exercise RetryPolicy exhausting its attempts, and http.StatusAccepted
+// to exercise handling of a long-running 202 response.
+func (s *Server) ForceStatus(code int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.forceStatus = code
+	s.failuresRemaining = 0
+}
+
+// FailNextRequests makes the next count requests respond with code, after which the server reverts to
+// its normal canned response. Use it with http.StatusTooManyRequests or http.StatusServiceUnavailable and
+// a count below RetryPolicy.MaxAttempts to exercise the retry-then-succeed path, as opposed to
+// ForceStatus's retry-until-exhausted path.
+func (s *Server) FailNextRequests(code int, count int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if count <= 0 {
+		code = 0
+	}
+	s.forceStatus = code
+	s.failuresRemaining = count
+}
+
+// SetOperations replaces the canned page returned by AvailableOperationsClient.List and ListComplete.
+func (s *Server) SetOperations(operations []vmwarecloudsimple.AvailableOperation) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.operations = operations
+}
+
+func (s *Server) handleOperations(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	status := s.forceStatus
+	if s.failuresRemaining > 0 {
+		s.failuresRemaining--
+		if s.failuresRemaining == 0 {
+			s.forceStatus = 0
+		}
+	}
+	operations := s.operations
+	s.mu.Unlock()
+
+	switch status {
+	case 0:
+	case http.StatusAccepted:
+		w.Header().Set("Azure-AsyncOperation", s.URL+r.URL.Path)
+		w.Header().Set("Location", s.URL+r.URL.Path)
+		w.WriteHeader(http.StatusAccepted)
+		return
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(status)
+		return
+	default:
+		w.WriteHeader(status)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	// On the first page, nextLink points back at this same endpoint with a page cursor, driving one
+	// more NextWithContext call so ListComplete and the prefetch iterator can be exercised against a
+	// real two-page response; the second page omits nextLink and terminates the list.
+	resp := struct {
+		Value    []vmwarecloudsimple.AvailableOperation `json:"value"`
+		NextLink *string                                `json:"nextLink,omitempty"`
+	}{Value: operations}
+	if r.URL.Query().Get(operationsPage) == "" {
+		next := s.URL + r.URL.Path + "?" + operationsPage + "=2"
+		resp.NextLink = &next
+	}
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// NewClient returns an AvailableOperationsClient wired to a fresh fake server. The server is shut down
+// automatically via t.Cleanup.
+func NewClient(t *testing.T) vmwarecloudsimple.AvailableOperationsClient {
+	s := NewServer(t)
+	return vmwarecloudsimple.NewAvailableOperationsClientWithBaseURI(s.URL, "", "", "")
+}
@@ -0,0 +1,121 @@
+package vmwarecloudsimple
+
+// Copyright (c) Microsoft and contributors.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import "context"
+
+// AvailableOperationsListResponsePagePrefetch wraps an AvailableOperationsListResponsePage, fetching up
+// to depth pages ahead on a background goroutine while the caller consumes the current one. It is an
+// opt-in alternative to AvailableOperationsListResponsePage for callers enumerating large ARM lists who
+// want fetch latency overlapped with processing.
+type AvailableOperationsListResponsePagePrefetch struct {
+	cancel context.CancelFunc
+	pages  chan prefetchedAvailableOperationsListResponsePage
+	cur    AvailableOperationsListResponsePage
+	err    error
+}
+
+type prefetchedAvailableOperationsListResponsePage struct {
+	page AvailableOperationsListResponsePage
+	err  error
+}
+
+// NewAvailableOperationsListResponsePagePrefetch fetches the first page synchronously, then starts a
+// background goroutine that fetches up to depth further pages ahead into a bounded channel. Cancelling
+// ctx stops the background fetch; a caller that falls behind applies back-pressure because the channel
+// buffers depth-1 pages and the goroutine holds one more while it waits to send. Any error encountered while prefetching is surfaced from the next call to
+// NextWithContext rather than returned here.
+func NewAvailableOperationsListResponsePagePrefetch(ctx context.Context, client AvailableOperationsClient, depth int) (*AvailableOperationsListResponsePagePrefetch, error) {
+	if depth < 1 {
+		depth = 1
+	}
+
+	first, err := client.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	runCtx, cancel := context.WithCancel(ctx)
+	p := &AvailableOperationsListResponsePagePrefetch{
+		cancel: cancel,
+		pages:  make(chan prefetchedAvailableOperationsListResponsePage, depth-1),
+		cur:    first,
+	}
+	go p.run(runCtx, first)
+	return p, nil
+}
+
+func (p *AvailableOperationsListResponsePagePrefetch) run(ctx context.Context, page AvailableOperationsListResponsePage) {
+	defer close(p.pages)
+	for page.NotDone() {
+		if err := page.NextWithContext(ctx); err != nil {
+			select {
+			case p.pages <- prefetchedAvailableOperationsListResponsePage{err: err}:
+			case <-ctx.Done():
+			}
+			return
+		}
+		select {
+		case p.pages <- prefetchedAvailableOperationsListResponsePage{page: page}:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// NextWithContext advances to the next prefetched page, blocking until the background fetch has it
+// ready or ctx is cancelled. An error surfaced here may have originated from a fetch that ran ahead of
+// this call.
+func (p *AvailableOperationsListResponsePagePrefetch) NextWithContext(ctx context.Context) error {
+	if p.err != nil {
+		return p.err
+	}
+	select {
+	case next, ok := <-p.pages:
+		if !ok {
+			return nil
+		}
+		if next.err != nil {
+			p.err = next.err
+			return next.err
+		}
+		p.cur = next.page
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Next advances to the next prefetched page. It is a wrapper around NextWithContext using
+// context.Background().
+func (p *AvailableOperationsListResponsePagePrefetch) Next() error {
+	return p.NextWithContext(context.Background())
+}
+
+// NotDone reports whether Response returns a valid page.
+func (p *AvailableOperationsListResponsePagePrefetch) NotDone() bool {
+	return p.err == nil && p.cur.NotDone()
+}
+
+// Response returns the current prefetched page.
+func (p *AvailableOperationsListResponsePagePrefetch) Response() AvailableOperationsListResponsePage {
+	return p.cur
+}
+
+// Close stops the background prefetch goroutine. Callers should call it once they are done consuming
+// pages, including when abandoning enumeration early.
+func (p *AvailableOperationsListResponsePagePrefetch) Close() {
+	p.cancel()
+}
@@ -0,0 +1,175 @@
+package vmwarecloudsimple
+
+// Copyright (c) Microsoft and contributors.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"io"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Azure/go-autorest/autorest"
+)
+
+// RetryPolicy configures how BaseClient retries a failed request. The zero value is not usable with
+// DoRetryWithPolicy directly, since a MaxAttempts of zero makes no attempt; the package's senders
+// substitute DefaultRetryPolicy() when BaseClient.RetryPolicy.MaxAttempts is zero.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of times a request is attempted, including the first try.
+	MaxAttempts int
+	// InitialBackoff is the backoff applied after the first failed attempt.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the computed backoff regardless of attempt count.
+	MaxBackoff time.Duration
+	// Multiplier grows the backoff between attempts.
+	Multiplier float64
+	// Jitter applies full jitter (a random duration between 0 and the computed backoff) when true.
+	Jitter bool
+	// RespectRetryAfter honors a Retry-After response header, in seconds or HTTP-date form, over the
+	// computed backoff.
+	RespectRetryAfter bool
+	// RetryableStatusCodes lists the status codes that trigger a retry, in addition to network errors.
+	RetryableStatusCodes []int
+	// PerTryTimeout bounds a single attempt; zero means no per-attempt timeout.
+	PerTryTimeout time.Duration
+}
+
+// DefaultRetryPolicy is the policy BaseClient falls back to when RetryPolicy.MaxAttempts is zero.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:          3,
+		InitialBackoff:       time.Second,
+		MaxBackoff:           time.Minute,
+		Multiplier:           2,
+		Jitter:               true,
+		RespectRetryAfter:    true,
+		RetryableStatusCodes: []int{http.StatusTooManyRequests, http.StatusServiceUnavailable},
+	}
+}
+
+// DoRetryWithPolicy returns a SendDecorator that retries a request per policy, applying truncated
+// exponential backoff with full jitter between attempts and honoring Retry-After when
+// policy.RespectRetryAfter is set. The wait between attempts also observes the request's own context, so
+// a cancelled request doesn't sleep out the full backoff before returning. Like the
+// autorest.DoRetryForStatusCodes decorator it supplants, it drains and closes the prior response's body
+// before retrying and rewinds the request body via autorest.NewRetriableRequest, so it neither leaks the
+// prior connection nor resends an already-consumed body.
+func DoRetryWithPolicy(policy RetryPolicy) autorest.SendDecorator {
+	return func(s autorest.Sender) autorest.Sender {
+		return autorest.SenderFunc(func(r *http.Request) (resp *http.Response, err error) {
+			rr := autorest.NewRetriableRequest(r)
+			for attempt := 0; attempt < policy.MaxAttempts; attempt++ {
+				if err = rr.Prepare(); err != nil {
+					return resp, err
+				}
+				autorest.DrainResponseBody(resp)
+
+				req := rr.Request()
+				var cancel context.CancelFunc
+				if policy.PerTryTimeout > 0 {
+					var ctx context.Context
+					ctx, cancel = context.WithTimeout(req.Context(), policy.PerTryTimeout)
+					req = req.WithContext(ctx)
+				}
+				resp, err = s.Do(req)
+				if err == nil && !isRetryableStatusCode(resp.StatusCode, policy.RetryableStatusCodes) {
+					// Success: the response body is still bound to the per-try context, so don't
+					// cancel it out from under the caller - tie cancellation to the body's Close
+					// instead, so it fires once the responder is done reading it.
+					if cancel != nil {
+						resp.Body = &cancelOnCloseBody{ReadCloser: resp.Body, cancel: cancel}
+					}
+					return resp, nil
+				}
+				if cancel != nil {
+					cancel()
+				}
+				if attempt == policy.MaxAttempts-1 {
+					return resp, err
+				}
+				select {
+				case <-time.After(retryDelay(policy, attempt, resp)):
+				case <-r.Context().Done():
+					autorest.DrainResponseBody(resp)
+					return resp, r.Context().Err()
+				}
+			}
+			return resp, err
+		})
+	}
+}
+
+// cancelOnCloseBody releases a per-try context's resources once the wrapped body is closed, instead of
+// up front, so the timeout can't invalidate a response the caller hasn't finished reading yet.
+type cancelOnCloseBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnCloseBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+func isRetryableStatusCode(code int, codes []int) bool {
+	for _, c := range codes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+func retryDelay(policy RetryPolicy, attempt int, resp *http.Response) time.Duration {
+	if policy.RespectRetryAfter {
+		if d, ok := retryAfter(resp); ok {
+			if policy.MaxBackoff > 0 && d > policy.MaxBackoff {
+				d = policy.MaxBackoff
+			}
+			return d
+		}
+	}
+	backoff := float64(policy.InitialBackoff) * math.Pow(policy.Multiplier, float64(attempt))
+	if policy.MaxBackoff > 0 && backoff > float64(policy.MaxBackoff) {
+		backoff = float64(policy.MaxBackoff)
+	}
+	if !policy.Jitter {
+		return time.Duration(backoff)
+	}
+	return time.Duration(rand.Float64() * backoff)
+}
+
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	if resp == nil {
+		return 0, false
+	}
+	ra := resp.Header.Get("Retry-After")
+	if ra == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(ra); err == nil {
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(ra); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d, true
+		}
+	}
+	return 0, false
+}